@@ -0,0 +1,57 @@
+package gowebdav
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSelectAuthFactoryPrefersDigestOverBasic(t *testing.T) {
+	scheme, _, ok := selectAuthFactory(`Digest realm="x", Basic realm="x"`, nil)
+	if !ok || scheme != "digest" {
+		t.Errorf("scheme = %q, ok = %v, want \"digest\" (stronger scheme wins)", scheme, ok)
+	}
+}
+
+func TestSelectAuthFactoryNegotiateBundle(t *testing.T) {
+	// SharePoint-style header advertising several schemes at once.
+	scheme, _, ok := selectAuthFactory("Negotiate, NTLM, Basic realm=\"x\"", nil)
+	if !ok || scheme != "ntlm" {
+		t.Errorf("scheme = %q, ok = %v, want \"ntlm\"", scheme, ok)
+	}
+}
+
+func TestSelectAuthFactoryDeterministicAcrossCalls(t *testing.T) {
+	header := `Digest realm="x", Basic realm="x"`
+	first, _, _ := selectAuthFactory(header, nil)
+	for i := 0; i < 20; i++ {
+		scheme, _, _ := selectAuthFactory(header, nil)
+		if scheme != first {
+			t.Fatalf("call %d picked %q, first call picked %q: selection must be deterministic", i, scheme, first)
+		}
+	}
+}
+
+func TestSelectAuthFactoryCustomOverridesDefault(t *testing.T) {
+	called := false
+	custom := map[string]AuthFactory{
+		"basic": func(rs *http.Response, user, pass string) Authorizer {
+			called = true
+			return nil
+		},
+	}
+
+	scheme, factory, ok := selectAuthFactory(`Basic realm="x"`, custom)
+	if !ok || scheme != "basic" {
+		t.Fatalf("scheme = %q, ok = %v, want \"basic\"", scheme, ok)
+	}
+	factory(nil, "u", "p")
+	if !called {
+		t.Error("want the custom \"basic\" factory to be used, not the built-in one")
+	}
+}
+
+func TestSelectAuthFactoryNoMatch(t *testing.T) {
+	if _, _, ok := selectAuthFactory("Negotiate", nil); ok {
+		t.Error("want ok=false when no known scheme is advertised")
+	}
+}
@@ -0,0 +1,107 @@
+package gowebdav
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrNotSupported is returned by operations that consulted Capabilities and
+// found the server doesn't advertise what they need, instead of blindly
+// issuing a request the server would just reject.
+var ErrNotSupported = errors.New("gowebdav: not supported by server")
+
+// Capabilities describes what a WebDAV server advertises via its OPTIONS
+// response: the DAV compliance classes, the methods it allows, and a fixed
+// set of vendor capability headers. Client.Capabilities
+// caches one of these per client after the first call.
+type Capabilities struct {
+	// Class holds the DAV compliance classes from the `DAV:` header, e.g.
+	// "1", "2", "3", "extended-mkcol", "access-control", "calendar-access".
+	Class []string
+
+	// Allow holds the methods from the `Allow` header.
+	Allow []string
+
+	// Vendor holds the "OC-Checksums", "X-Sabre-Version" and
+	// "OC-Capabilities" headers, when present, keyed by exactly those names.
+	// Other vendor headers are not recorded.
+	Vendor map[string]string
+}
+
+// Supports reports whether class (a single DAV compliance token, matched
+// case-insensitively) is present.
+func (caps Capabilities) Supports(class string) bool {
+	for _, c := range caps.Class {
+		if strings.EqualFold(c, class) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether method is present in the Allow header.
+func (caps Capabilities) Allows(method string) bool {
+	for _, m := range caps.Allow {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// Capabilities discovers and caches the server's DAV compliance classes,
+// allowed methods, and vendor capability headers by issuing (once) an
+// OPTIONS request against the client's root. Subsequent calls return the
+// cached value. Lock consults it and returns ErrNotSupported when class 2
+// is not advertised; put consults it to auto-enable chunked upload. PropFind
+// does not consult it, whatever the requested Depth.
+func (c *Client) Capabilities(ctx context.Context) (Capabilities, error) {
+	c.capsMutex.Lock()
+	if c.caps != nil {
+		caps := *c.caps
+		c.capsMutex.Unlock()
+		return caps, nil
+	}
+	c.capsMutex.Unlock()
+
+	rs, err := c.options(ctx, "/")
+	if err != nil {
+		return Capabilities{}, err
+	}
+	defer rs.Body.Close()
+
+	caps := Capabilities{
+		Class:  splitHeaderList(rs.Header.Get("Dav")),
+		Allow:  splitHeaderList(rs.Header.Get("Allow")),
+		Vendor: make(map[string]string),
+	}
+	for _, h := range []string{"OC-Checksums", "X-Sabre-Version", "OC-Capabilities"} {
+		if v := rs.Header.Get(h); v != "" {
+			caps.Vendor[h] = v
+		}
+	}
+
+	c.capsMutex.Lock()
+	c.caps = &caps
+	c.capsMutex.Unlock()
+
+	return caps, nil
+}
+
+// splitHeaderList splits a comma-separated header value (DAV:, Allow: ...)
+// into trimmed, non-empty tokens.
+func splitHeaderList(v string) []string {
+	if v == "" {
+		return nil
+	}
+	parts := strings.Split(v, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package gowebdav
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitHeaderList(t *testing.T) {
+	cases := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"1", []string{"1"}},
+		{"1, 2, 3, extended-mkcol", []string{"1", "2", "3", "extended-mkcol"}},
+		{" 1 ,2 ", []string{"1", "2"}},
+	}
+
+	for _, c := range cases {
+		got := splitHeaderList(c.in)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("splitHeaderList(%q) = %#v, want %#v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestCapabilitiesSupportsAndAllows(t *testing.T) {
+	caps := Capabilities{
+		Class: []string{"1", "2", "extended-mkcol"},
+		Allow: []string{"GET", "PUT", "PROPFIND"},
+	}
+
+	if !caps.Supports("2") {
+		t.Error("want Supports(\"2\") to be true")
+	}
+	if caps.Supports("3") {
+		t.Error("want Supports(\"3\") to be false")
+	}
+	if !caps.Allows("put") {
+		t.Error("want Allows to match case-insensitively")
+	}
+	if caps.Allows("DELETE") {
+		t.Error("want Allows(\"DELETE\") to be false")
+	}
+}
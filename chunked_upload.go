@@ -0,0 +1,282 @@
+package gowebdav
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// readerLength reports the number of bytes remaining in r, if r supports
+// seeking (e.g. an *os.File). It leaves r positioned where it found it.
+func readerLength(r io.Reader) (int64, bool) {
+	sk, ok := r.(io.Seeker)
+	if !ok {
+		return 0, false
+	}
+	cur, err := sk.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, false
+	}
+	end, err := sk.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, false
+	}
+	if _, err := sk.Seek(cur, io.SeekStart); err != nil {
+		return 0, false
+	}
+	return end - cur, true
+}
+
+// chunkedUploadConfig holds the tuning set by SetChunkedUpload. A zero value
+// (chunkSize == 0) means chunked upload is disabled and put falls back to a
+// single-shot PUT.
+type chunkedUploadConfig struct {
+	chunkSize   int64
+	concurrency int
+	checksum    bool
+}
+
+// SetChunkedUpload enables Nextcloud/ownCloud chunking v2 for subsequent
+// writes: files are split into chunkSize byte pieces and PUT in parallel
+// (bounded by concurrency) into a temporary upload collection before being
+// assembled server-side with a MOVE. This avoids proxy/timeout limits that a
+// single-shot PUT of a multi-GB file tends to hit against Nextcloud.
+//
+// Passing chunkSize <= 0 disables chunked upload again.
+func (c *Client) SetChunkedUpload(chunkSize int64, concurrency int) {
+	c.chunkMutex.Lock()
+	defer c.chunkMutex.Unlock()
+
+	if chunkSize <= 0 {
+		c.chunkUpload = nil
+		return
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	c.chunkUpload = &chunkedUploadConfig{chunkSize: chunkSize, concurrency: concurrency}
+}
+
+// SetChunkedUploadChecksum toggles whether each chunk is sent with an
+// OC-Checksum header computed from its SHA-256 digest, letting the server
+// detect corruption in transit. Has no effect unless SetChunkedUpload was
+// called first.
+func (c *Client) SetChunkedUploadChecksum(enabled bool) {
+	c.chunkMutex.Lock()
+	defer c.chunkMutex.Unlock()
+
+	if c.chunkUpload != nil {
+		c.chunkUpload.checksum = enabled
+	}
+}
+
+// defaultChunkSize and defaultChunkConcurrency tune chunked upload when it
+// kicks in because the server advertised support for it, rather than
+// because the caller configured it explicitly via SetChunkedUpload.
+const (
+	defaultChunkSize        = 10 << 20 // 10 MiB
+	defaultChunkConcurrency = 4
+)
+
+// chunkedUploadConfigFor decides whether path's length-byte upload should
+// go through chunkedPut. An explicit SetChunkedUpload call is sufficient on
+// its own: it's the user directly telling us this server wants chunking,
+// and second-guessing it by additionally requiring capability detection to
+// agree would defeat the point of the setting. Capability detection only
+// kicks in to *auto-enable* chunking when the caller configured nothing.
+func (c *Client) chunkedUploadConfigFor(ctx context.Context, length int64) *chunkedUploadConfig {
+	c.chunkMutex.Lock()
+	cfg := c.chunkUpload
+	c.chunkMutex.Unlock()
+
+	if cfg != nil {
+		if length <= cfg.chunkSize {
+			return nil
+		}
+		return cfg
+	}
+
+	if length <= defaultChunkSize || !c.supportsChunkedUpload(ctx) {
+		return nil
+	}
+	return &chunkedUploadConfig{chunkSize: defaultChunkSize, concurrency: defaultChunkConcurrency}
+}
+
+// supportsChunkedUpload reports whether the server looks like an
+// ownCloud/Nextcloud deployment capable of chunking v2. OC-Checksums alone
+// only signals checksum support, not chunking, so it's combined with
+// X-Sabre-Version (present on every sabre/dav-based server, which is what
+// ownCloud/Nextcloud build on) to avoid auto-enabling chunked upload against
+// a server that merely happens to echo one unrelated header.
+func (c *Client) supportsChunkedUpload(ctx context.Context) bool {
+	caps, err := c.Capabilities(ctx)
+	if err != nil {
+		return false
+	}
+	_, sabre := caps.Vendor["X-Sabre-Version"]
+	_, checksums := caps.Vendor["OC-Checksums"]
+	return sabre && checksums
+}
+
+// uploadsRoot returns the absolute URL of the Nextcloud/ownCloud chunking
+// v2 uploads endpoint, e.g. "https://host/remote.php/dav/uploads". This is
+// a sibling of c.root (typically ".../remote.php/dav/files/<user>/"), not a
+// child of it, so it's derived from c.root's scheme/host rather than
+// Join-ed onto c.root the way every other request path is.
+func (c *Client) uploadsRoot() (string, error) {
+	return uploadsRootFromBase(c.root)
+}
+
+// uploadsRootFromBase is the pure logic behind uploadsRoot, split out so it
+// can be tested without constructing a Client.
+func uploadsRootFromBase(root string) (string, error) {
+	u, err := url.Parse(root)
+	if err != nil {
+		return "", err
+	}
+	u.Path = "/remote.php/dav/uploads"
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+// chunkedPut uploads stream (of the given total length) to path using the
+// Nextcloud chunking v2 protocol: a temporary upload directory is created,
+// fixed-size chunks are PUT in parallel, and the result is assembled with a
+// MOVE carrying OC-Total-Length. Individual chunk failures are retried once
+// before the whole transfer is given up on.
+func (c *Client) chunkedPut(ctx context.Context, path string, stream io.Reader, length int64, cfg *chunkedUploadConfig) (status int, err error) {
+	uploadsRoot, err := c.uploadsRoot()
+	if err != nil {
+		return 0, err
+	}
+
+	uploadID := randomID()
+	uploadDir := Join(uploadsRoot, c.currentUser(), uploadID)
+
+	if _, err = c.mkcol(ctx, uploadDir); err != nil {
+		return 0, err
+	}
+
+	numChunks := (length + cfg.chunkSize - 1) / cfg.chunkSize
+
+	sem := make(chan struct{}, cfg.concurrency)
+	var wg sync.WaitGroup
+	errs := make([]error, numChunks)
+
+	for i := int64(0); i < numChunks; i++ {
+		offset := i * cfg.chunkSize
+		size := cfg.chunkSize
+		if offset+size > length {
+			size = length - offset
+		}
+		chunk := make([]byte, size)
+		if _, rerr := io.ReadFull(stream, chunk); rerr != nil {
+			return 0, rerr
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int64, offset int64, data []byte) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[idx] = c.putChunkWithRetry(ctx, uploadDir, idx, offset, data, cfg)
+		}(i, offset, chunk)
+	}
+	wg.Wait()
+
+	for _, e := range errs {
+		if e != nil {
+			return 0, e
+		}
+	}
+
+	rs, err := c.req(ctx, "MOVE", Join(uploadDir, ".file"), nil, func(rq *http.Request) {
+		rq.Header.Set("Destination", PathEscape(Join(c.root, path)))
+		rq.Header.Set("OC-Total-Length", strconv.FormatInt(length, 10))
+		rq.Header.Set("Overwrite", "T")
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer rs.Body.Close()
+
+	return rs.StatusCode, nil
+}
+
+func (c *Client) putChunkWithRetry(ctx context.Context, uploadDir string, idx int64, offset int64, data []byte, cfg *chunkedUploadConfig) error {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if err = c.putChunk(ctx, uploadDir, offset, data, cfg); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (c *Client) putChunk(ctx context.Context, uploadDir string, offset int64, data []byte, cfg *chunkedUploadConfig) error {
+	name := chunkName(offset)
+	rs, err := c.req(ctx, "PUT", Join(uploadDir, name), bytes.NewReader(data), func(rq *http.Request) {
+		for k, v := range chunkHeaders(offset, data, cfg) {
+			rq.Header.Set(k, v)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	defer rs.Body.Close()
+
+	if rs.StatusCode != 201 && rs.StatusCode != 204 {
+		return newPathError("PUT", Join(uploadDir, name), rs.StatusCode)
+	}
+	return nil
+}
+
+// chunkName is the numeric filename a chunk is PUT under, per the
+// Nextcloud chunking v2 protocol: its byte offset within the final file.
+func chunkName(offset int64) string {
+	return strconv.FormatInt(offset, 10)
+}
+
+// chunkHeaders builds the per-chunk headers for putChunk: always
+// OC-Chunk-Offset, plus an OC-Checksum of data's SHA-256 when cfg asked for
+// one. Split out from putChunk so the offset/checksum logic can be tested
+// without a Client.
+func chunkHeaders(offset int64, data []byte, cfg *chunkedUploadConfig) map[string]string {
+	h := map[string]string{"OC-Chunk-Offset": chunkName(offset)}
+	if cfg.checksum {
+		sum := sha256.Sum256(data)
+		h["OC-Checksum"] = "SHA256:" + hex.EncodeToString(sum[:])
+	}
+	return h
+}
+
+// currentUser returns the username chunked upload paths are namespaced
+// under, falling back to "_" when the configured auth has none (e.g. an
+// anonymous or token-based provider).
+func (c *Client) currentUser() string {
+	c.authMutex.Lock()
+	auth := c.auth
+	c.authMutex.Unlock()
+
+	if u := auth.User(); u != "" {
+		return u
+	}
+	return "_"
+}
+
+func randomID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%d", len(buf))
+	}
+	return hex.EncodeToString(buf)
+}
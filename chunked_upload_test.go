@@ -0,0 +1,105 @@
+package gowebdav
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReaderLengthSeekable(t *testing.T) {
+	r := bytes.NewReader([]byte("hello world"))
+	if _, err := r.Seek(2, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	length, ok := readerLength(r)
+	if !ok {
+		t.Fatal("want ok=true for a seekable reader")
+	}
+	if length != int64(len("hello world"))-2 {
+		t.Errorf("length = %d, want remaining bytes from current position", length)
+	}
+
+	// Cursor must not have moved.
+	got, _ := io.ReadAll(r)
+	if string(got) != "llo world" {
+		t.Errorf("readerLength moved the cursor: read %q afterwards", got)
+	}
+}
+
+func TestReaderLengthNonSeekable(t *testing.T) {
+	plain := onlyReader{strings.NewReader("hello")}
+	if _, ok := readerLength(plain); ok {
+		t.Error("want ok=false for a reader that isn't also a Seeker")
+	}
+}
+
+// onlyReader hides *strings.Reader's Seek method so readerLength sees a
+// plain io.Reader, the case a non-*os.File caller (e.g. a network stream)
+// hits.
+type onlyReader struct {
+	r *strings.Reader
+}
+
+func (o onlyReader) Read(p []byte) (int, error) { return o.r.Read(p) }
+
+func TestChunkHeadersOffset(t *testing.T) {
+	cfg := &chunkedUploadConfig{chunkSize: 1024, concurrency: 1}
+
+	h := chunkHeaders(2048, []byte("data"), cfg)
+	if h["OC-Chunk-Offset"] != "2048" {
+		t.Errorf("OC-Chunk-Offset = %q, want %q", h["OC-Chunk-Offset"], "2048")
+	}
+	if _, ok := h["OC-Checksum"]; ok {
+		t.Error("want no OC-Checksum when cfg.checksum is false")
+	}
+}
+
+func TestChunkHeadersChecksum(t *testing.T) {
+	cfg := &chunkedUploadConfig{chunkSize: 1024, concurrency: 1, checksum: true}
+	data := []byte("data")
+
+	h := chunkHeaders(0, data, cfg)
+
+	sum := sha256.Sum256(data)
+	want := "SHA256:" + hex.EncodeToString(sum[:])
+	if h["OC-Checksum"] != want {
+		t.Errorf("OC-Checksum = %q, want %q", h["OC-Checksum"], want)
+	}
+}
+
+func TestChunkName(t *testing.T) {
+	if got := chunkName(4096); got != "4096" {
+		t.Errorf("chunkName(4096) = %q, want %q", got, "4096")
+	}
+}
+
+func TestUploadsRootFromBaseIsHostSiblingNotFilesChild(t *testing.T) {
+	// c.root for Nextcloud is the per-user files root; the chunking v2
+	// uploads endpoint is a sibling of it on the same host, not something
+	// Join(c.root, ...) should ever reach under /files/<user>/.
+	got, err := uploadsRootFromBase("https://host/remote.php/dav/files/alice/")
+	if err != nil {
+		t.Fatalf("uploadsRootFromBase: %v", err)
+	}
+	want := "https://host/remote.php/dav/uploads"
+	if got != want {
+		t.Errorf("uploadsRootFromBase = %q, want %q", got, want)
+	}
+}
+
+func TestResolveRequestURLAbsolutePathBypassesRoot(t *testing.T) {
+	root := "https://host/remote.php/dav/files/alice/"
+	abs := "https://host/remote.php/dav/uploads/alice/id123"
+
+	got := resolveRequestURL(root, abs)
+	if strings.Contains(got, "files/alice") {
+		t.Errorf("resolveRequestURL(%q, %q) = %q, want the absolute path used as-is, not joined under root", root, abs, got)
+	}
+	if got != abs {
+		t.Errorf("resolveRequestURL = %q, want %q unchanged", got, abs)
+	}
+}
@@ -0,0 +1,94 @@
+package gowebdav
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newLockResponse(headers map[string]string, body string) *http.Response {
+	h := http.Header{}
+	for k, v := range headers {
+		h.Set(k, v)
+	}
+	return &http.Response{
+		Header: h,
+		Body:   io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestParseLockResponseFromHeader(t *testing.T) {
+	rs := newLockResponse(map[string]string{"Lock-Token": "<opaquelocktoken:abc-123>"}, "")
+
+	info, err := parseLockResponse(rs, "0", 30*time.Second)
+	if err != nil {
+		t.Fatalf("parseLockResponse: %v", err)
+	}
+	if info.Token != "opaquelocktoken:abc-123" {
+		t.Errorf("Token = %q, want unwrapped of angle brackets", info.Token)
+	}
+	if info.Depth != "0" || info.Timeout != 30*time.Second {
+		t.Errorf("Depth/Timeout = %q/%v, want requested values echoed back", info.Depth, info.Timeout)
+	}
+}
+
+func TestParseLockResponseFromBody(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<D:prop xmlns:D="DAV:">
+  <D:lockdiscovery>
+    <D:activelock>
+      <D:locktype><D:write/></D:locktype>
+      <D:lockscope><D:exclusive/></D:lockscope>
+      <D:depth>infinity</D:depth>
+      <D:timeout>Second-120</D:timeout>
+      <D:locktoken><D:href>opaquelocktoken:from-body</D:href></D:locktoken>
+    </D:activelock>
+  </D:lockdiscovery>
+</D:prop>`
+	rs := newLockResponse(nil, body)
+
+	info, err := parseLockResponse(rs, "0", 0)
+	if err != nil {
+		t.Fatalf("parseLockResponse: %v", err)
+	}
+	if info.Token != "opaquelocktoken:from-body" {
+		t.Errorf("Token = %q, want parsed from lockdiscovery body", info.Token)
+	}
+	if info.Depth != "infinity" {
+		t.Errorf("Depth = %q, want %q (from the server's activelock, not the request)", info.Depth, "infinity")
+	}
+	if info.Timeout != 120*time.Second {
+		t.Errorf("Timeout = %v, want 120s parsed from Second-120", info.Timeout)
+	}
+}
+
+func TestBuildLockBodyExclusiveNamespace(t *testing.T) {
+	body := buildLockBody(LockOpts{})
+	if !strings.Contains(body, "<D:lockscope><D:exclusive/></D:lockscope>") {
+		t.Errorf("body = %q, want a DAV:-namespaced <D:exclusive/> inside lockscope", body)
+	}
+}
+
+func TestBuildLockBodyShared(t *testing.T) {
+	body := buildLockBody(LockOpts{Shared: true})
+	if !strings.Contains(body, "<D:lockscope><D:shared/></D:lockscope>") {
+		t.Errorf("body = %q, want a DAV:-namespaced <D:shared/> inside lockscope", body)
+	}
+}
+
+func TestBuildLockBodyOwner(t *testing.T) {
+	body := buildLockBody(LockOpts{Owner: "mailto:me@example.com"})
+	if !strings.Contains(body, "<D:owner><D:href>mailto:me@example.com</D:href></D:owner>") {
+		t.Errorf("body = %q, want the owner rendered as an href", body)
+	}
+}
+
+func TestParseLockResponseNoActiveLock(t *testing.T) {
+	rs := newLockResponse(nil, `<?xml version="1.0"?><D:prop xmlns:D="DAV:"><D:lockdiscovery/></D:prop>`)
+
+	if _, err := parseLockResponse(rs, "0", 0); err != errNoActiveLock {
+		t.Errorf("err = %v, want errNoActiveLock", err)
+	}
+}
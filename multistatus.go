@@ -0,0 +1,182 @@
+package gowebdav
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MultiStatusEntry is one `<D:response>` from a 207 Multi-Status body,
+// describing the outcome for a single href within a COPY, MOVE or DELETE
+// that targeted a collection.
+type MultiStatusEntry struct {
+	Href        string
+	Status      int
+	Description string
+}
+
+// Failed reports whether this entry's status is outside the 2xx range.
+func (e MultiStatusEntry) Failed() bool {
+	return e.Status < 200 || e.Status >= 300
+}
+
+// MultiStatusError is returned by operations that targeted a collection and
+// got back a 207 response, instead of the previous behavior of silently
+// logging and returning nil. Entries holds every descendant href the
+// server reported on; callers can inspect which ones failed via Failures.
+type MultiStatusError struct {
+	Method  string
+	Path    string
+	Entries []MultiStatusEntry
+}
+
+func (e *MultiStatusError) Error() string {
+	return fmt.Sprintf("gowebdav: %s %s: %d descendants failed of %d", e.Method, e.Path, len(e.Failures()), len(e.Entries))
+}
+
+// Failures returns the subset of Entries whose status was not 2xx.
+func (e *MultiStatusError) Failures() []MultiStatusEntry {
+	var out []MultiStatusEntry
+	for _, ent := range e.Entries {
+		if ent.Failed() {
+			out = append(out, ent)
+		}
+	}
+	return out
+}
+
+type multiStatusXML struct {
+	XMLName   xml.Name `xml:"DAV: multistatus"`
+	Responses []struct {
+		Href     string `xml:"href"`
+		Status   string `xml:"status"`
+		PropStat []struct {
+			Status string `xml:"status"`
+		} `xml:"propstat"`
+		ResponseDescription string `xml:"responsedescription"`
+	} `xml:"response"`
+}
+
+// parseMultiStatus decodes a 207 Multi-Status response body into a
+// MultiStatusError describing the per-href outcome of method against path.
+// It always returns every entry the server reported, including 2xx ones;
+// callers that only care whether anything actually failed should check
+// len(ms.Failures()) == 0 before treating the result as an error.
+func parseMultiStatus(method, path string, body io.Reader) (*MultiStatusError, error) {
+	var parsed multiStatusXML
+	if err := xml.NewDecoder(body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+
+	result := &MultiStatusError{Method: method, Path: path}
+	for _, r := range parsed.Responses {
+		status := r.Status
+		if status == "" && len(r.PropStat) > 0 {
+			status = r.PropStat[0].Status
+		}
+		result.Entries = append(result.Entries, MultiStatusEntry{
+			Href:        r.Href,
+			Status:      parseStatusLine(status),
+			Description: r.ResponseDescription,
+		})
+	}
+	return result, nil
+}
+
+// parseStatusLine extracts the numeric code out of a `HTTP/1.1 207
+// Multi-Status` style status line.
+func parseStatusLine(status string) int {
+	fields := strings.Fields(status)
+	for _, f := range fields {
+		if code, err := strconv.Atoi(f); err == nil {
+			return code
+		}
+	}
+	return 0
+}
+
+// RemoveTree issues a DELETE against path and, like copymove, turns a 207
+// Multi-Status response (some descendant survived an ACL check) into a
+// *MultiStatusError instead of treating the outer call as a plain success.
+// Use this instead of Remove/RemoveAll when path is a collection whose
+// children might be individually rejected by the server.
+func (c *Client) RemoveTree(ctx context.Context, path string) error {
+	rs, err := c.req(ctx, "DELETE", path, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer rs.Body.Close()
+
+	switch rs.StatusCode {
+	case 200, 204, 404:
+		return nil
+
+	case 207:
+		ms, err := parseMultiStatus("DELETE", path, rs.Body)
+		if err != nil {
+			return err
+		}
+		if len(ms.Failures()) == 0 {
+			return nil
+		}
+		return ms
+	}
+
+	return newPathError("DELETE", path, rs.StatusCode)
+}
+
+// CopyTree copies oldpath to newpath like Copy, but when the server returns
+// a 207 Multi-Status because part of the tree couldn't be copied (e.g. an
+// ACL rejected a child), it retries just the failing descendant hrefs
+// instead of leaving the caller to re-run the whole copy.
+func (c *Client) CopyTree(ctx context.Context, oldpath, newpath string, overwrite bool) error {
+	return c.retryFailingSubtree(ctx, "COPY", oldpath, newpath, overwrite)
+}
+
+// MoveTree is CopyTree's counterpart for MOVE.
+func (c *Client) MoveTree(ctx context.Context, oldpath, newpath string, overwrite bool) error {
+	return c.retryFailingSubtree(ctx, "MOVE", oldpath, newpath, overwrite)
+}
+
+func (c *Client) retryFailingSubtree(ctx context.Context, method, oldpath, newpath string, overwrite bool) error {
+	err := c.copymove(ctx, method, oldpath, newpath, overwrite)
+	ms, ok := err.(*MultiStatusError)
+	if !ok {
+		return err
+	}
+
+	for _, ent := range ms.Failures() {
+		childOld := hrefToPath(c.root, ent.Href)
+		childNew := Join(newpath, strings.TrimPrefix(childOld, oldpath))
+		if err := c.copymove(ctx, method, childOld, childNew, overwrite); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hrefToPath resolves a server-absolute href from a 207 response (e.g.
+// "/dav/coll/child") back to a path relative to root, root being the full
+// base URL (e.g. "https://host/dav/") that Join(c.root, path) builds
+// requests against elsewhere in this package. Trimming root verbatim from
+// href is wrong since href never carries the scheme/host; only root's URL
+// path component should be stripped.
+func hrefToPath(root, href string) string {
+	rootPath := "/"
+	if u, err := url.Parse(root); err == nil && u.Path != "" {
+		rootPath = u.Path
+	}
+
+	rel := strings.TrimPrefix(href, strings.TrimSuffix(rootPath, "/"))
+	if decoded, err := url.PathUnescape(rel); err == nil {
+		rel = decoded
+	}
+	if rel == "" {
+		rel = "/"
+	}
+	return rel
+}
@@ -0,0 +1,89 @@
+package gowebdav
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMultiStatusMixedResults(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<D:multistatus xmlns:D="DAV:">
+  <D:response>
+    <D:href>/dav/coll/a</D:href>
+    <D:status>HTTP/1.1 200 OK</D:status>
+  </D:response>
+  <D:response>
+    <D:href>/dav/coll/b</D:href>
+    <D:status>HTTP/1.1 423 Locked</D:status>
+    <D:responsedescription>locked by another user</D:responsedescription>
+  </D:response>
+</D:multistatus>`
+
+	ms, err := parseMultiStatus("COPY", "/coll", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("parseMultiStatus: %v", err)
+	}
+	if len(ms.Entries) != 2 {
+		t.Fatalf("len(Entries) = %d, want 2", len(ms.Entries))
+	}
+
+	failures := ms.Failures()
+	if len(failures) != 1 {
+		t.Fatalf("len(Failures()) = %d, want 1", len(failures))
+	}
+	if failures[0].Href != "/dav/coll/b" || failures[0].Status != 423 {
+		t.Errorf("failure = %+v, want href /dav/coll/b status 423", failures[0])
+	}
+	if failures[0].Description != "locked by another user" {
+		t.Errorf("Description = %q", failures[0].Description)
+	}
+}
+
+func TestParseMultiStatusAllSucceeded(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<D:multistatus xmlns:D="DAV:">
+  <D:response>
+    <D:href>/dav/coll/a</D:href>
+    <D:status>HTTP/1.1 201 Created</D:status>
+  </D:response>
+</D:multistatus>`
+
+	ms, err := parseMultiStatus("COPY", "/coll", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("parseMultiStatus: %v", err)
+	}
+	if len(ms.Failures()) != 0 {
+		t.Errorf("want no failures when every entry is 2xx, got %d", len(ms.Failures()))
+	}
+}
+
+func TestParseStatusLine(t *testing.T) {
+	cases := map[string]int{
+		"HTTP/1.1 207 Multi-Status": 207,
+		"HTTP/1.1 423 Locked":       423,
+		"":                          0,
+		"garbage":                   0,
+	}
+	for in, want := range cases {
+		if got := parseStatusLine(in); got != want {
+			t.Errorf("parseStatusLine(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestHrefToPath(t *testing.T) {
+	cases := []struct {
+		root, href, want string
+	}{
+		{"https://host/dav/", "/dav/coll/child", "/coll/child"},
+		{"https://host/dav", "/dav/coll/child", "/coll/child"},
+		{"https://host/", "/coll/child", "/coll/child"},
+		{"https://host", "/coll/child", "/coll/child"},
+		{"https://host/dav/", "/dav/a%20b", "/a b"},
+	}
+	for _, c := range cases {
+		if got := hrefToPath(c.root, c.href); got != c.want {
+			t.Errorf("hrefToPath(%q, %q) = %q, want %q", c.root, c.href, got, c.want)
+		}
+	}
+}
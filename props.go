@@ -0,0 +1,245 @@
+package gowebdav
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Property is a single dead or live property to set or remove via
+// PropPatch, identified by its fully-qualified XML name (e.g.
+// {DAV:}getlastmodified, {http://owncloud.org/ns}fileid).
+type Property struct {
+	Name  xml.Name
+	Value string
+}
+
+// PropStatus is one property's typed result within a Response, mirroring a
+// single <D:propstat> block: the property's value (if the status was 2xx)
+// alongside its own status, since a single PROPFIND/PROPPATCH can report a
+// different outcome per property.
+type PropStatus struct {
+	Name   xml.Name
+	Status int
+	Value  string
+}
+
+// Response is one href's result from PropFind, with a typed
+// status per requested property rather than the squashed-into-FileInfo view
+// the internal propfind/ParseInfo path gives callers.
+type Response struct {
+	Href  string
+	Props []PropStatus
+}
+
+type propfindPropRaw struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content string     `xml:",innerxml"`
+}
+
+type propfindResponseXML struct {
+	Href     string `xml:"href"`
+	PropStat []struct {
+		Status string `xml:"status"`
+		Prop   struct {
+			Items []propfindPropRaw `xml:",any"`
+		} `xml:"prop"`
+	} `xml:"propstat"`
+}
+
+type propfindMultistatusXML struct {
+	XMLName   xml.Name              `xml:"DAV: multistatus"`
+	Responses []propfindResponseXML `xml:"response"`
+}
+
+// PropPatchError is returned by PropPatch when the server's 207 response
+// reports a non-2xx status for one or more of the requested properties.
+// PROPPATCH always answers 207 regardless of outcome, so a bare "status
+// code was 207" check can't distinguish "everything applied" from "the
+// server rejected getlastmodified with 409" - Props holds exactly the
+// properties that failed, each with the status the server gave it.
+type PropPatchError struct {
+	Path  string
+	Props []PropStatus
+}
+
+func (e *PropPatchError) Error() string {
+	return fmt.Sprintf("gowebdav: PROPPATCH %s: %d propert(ies) rejected", e.Path, len(e.Props))
+}
+
+// PropFind requests props (or all properties, if props is empty) for path
+// at the given Depth ("0", "1" or "infinity"), returning one Response per
+// href with a typed status per property. Unlike the internal propfind,
+// which hard-codes its request body and squashes results into FileInfo,
+// this exposes arbitrary dead/live properties - checksums, fileid, quota,
+// custom app namespaces - to the caller.
+func (c *Client) PropFind(ctx context.Context, path string, depth string, props []xml.Name) ([]Response, error) {
+	if depth == "" {
+		depth = "0"
+	}
+
+	body := buildPropfindBody(props)
+
+	rs, err := c.req(ctx, "PROPFIND", path, strings.NewReader(body), func(rq *http.Request) {
+		rq.Header.Add("Depth", depth)
+		rq.Header.Add("Content-Type", "application/xml;charset=UTF-8")
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Body.Close()
+
+	if rs.StatusCode != 207 {
+		return nil, newPathError("PropFind", path, rs.StatusCode)
+	}
+
+	var parsed propfindMultistatusXML
+	if err := xml.NewDecoder(rs.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+
+	var out []Response
+	for _, r := range parsed.Responses {
+		resp := Response{Href: r.Href}
+		for _, ps := range r.PropStat {
+			status := parseStatusLine(ps.Status)
+			for _, p := range ps.Prop.Items {
+				resp.Props = append(resp.Props, PropStatus{
+					Name:   p.XMLName,
+					Status: status,
+					Value:  strings.TrimSpace(p.Content),
+				})
+			}
+		}
+		out = append(out, resp)
+	}
+	return out, nil
+}
+
+// buildPropfindBody renders an `<D:allprop/>` request when props is empty,
+// or an explicit `<D:prop>` listing each requested name (declaring a
+// namespace prefix per distinct XML namespace) otherwise.
+func buildPropfindBody(props []xml.Name) string {
+	if len(props) == 0 {
+		return `<?xml version="1.0" encoding="utf-8"?><D:propfind xmlns:D="DAV:"><D:allprop/></D:propfind>`
+	}
+
+	ns := map[string]string{"DAV:": "D"}
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?><D:propfind xmlns:D="DAV:"><D:prop>`)
+	for _, p := range props {
+		prefix, ok := ns[p.Space]
+		if !ok {
+			prefix = fmt.Sprintf("ns%d", len(ns))
+			ns[p.Space] = prefix
+		}
+		b.WriteString(fmt.Sprintf(`<%s:%s xmlns:%s="%s"/>`, prefix, p.Local, prefix, p.Space))
+	}
+	b.WriteString(`</D:prop></D:propfind>`)
+	return b.String()
+}
+
+// PropPatch sets and/or removes properties on path via PROPPATCH. Each
+// Property in set is rendered with its literal Value as element content;
+// each in remove is rendered empty inside a <D:remove> block. This is how
+// callers preserve {DAV:}getlastmodified across an upload, since PUT alone
+// can't set a server-side modtime.
+func (c *Client) PropPatch(ctx context.Context, path string, set, remove []Property) error {
+	body := buildProppatchBody(set, remove)
+
+	rs, err := c.req(ctx, "PROPPATCH", path, strings.NewReader(body), func(rq *http.Request) {
+		rq.Header.Add("Content-Type", "application/xml;charset=UTF-8")
+	})
+	if err != nil {
+		return err
+	}
+	defer rs.Body.Close()
+
+	switch rs.StatusCode {
+	case 200:
+		return nil
+
+	case 207:
+		return parsePropPatchResult(path, rs.Body)
+	}
+	return newPathError("PropPatch", path, rs.StatusCode)
+}
+
+// parsePropPatchResult decodes a 207 PROPPATCH response and fails if any
+// property's own propstat is outside the 2xx range. A 207 status code by
+// itself only means "here is a per-property report"; it does not mean the
+// properties were actually set or removed.
+func parsePropPatchResult(path string, body io.Reader) error {
+	var parsed propfindMultistatusXML
+	if err := xml.NewDecoder(body).Decode(&parsed); err != nil {
+		return err
+	}
+
+	var failed []PropStatus
+	for _, r := range parsed.Responses {
+		for _, ps := range r.PropStat {
+			status := parseStatusLine(ps.Status)
+			if status < 200 || status >= 300 {
+				for _, p := range ps.Prop.Items {
+					failed = append(failed, PropStatus{Name: p.XMLName, Status: status})
+				}
+			}
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return &PropPatchError{Path: path, Props: failed}
+}
+
+func buildProppatchBody(set, remove []Property) string {
+	ns := map[string]string{"DAV:": "D"}
+	prefixFor := func(space string) string {
+		prefix, ok := ns[space]
+		if !ok {
+			prefix = fmt.Sprintf("ns%d", len(ns))
+			ns[space] = prefix
+		}
+		return prefix
+	}
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?><D:propertyupdate xmlns:D="DAV:">`)
+
+	if len(set) > 0 {
+		b.WriteString(`<D:set><D:prop>`)
+		for _, p := range set {
+			prefix := prefixFor(p.Name.Space)
+			b.WriteString(fmt.Sprintf(`<%s:%s xmlns:%s="%s">%s</%s:%s>`,
+				prefix, p.Name.Local, prefix, p.Name.Space, xmlEscape(p.Value), prefix, p.Name.Local))
+		}
+		b.WriteString(`</D:prop></D:set>`)
+	}
+
+	if len(remove) > 0 {
+		b.WriteString(`<D:remove><D:prop>`)
+		for _, p := range remove {
+			prefix := prefixFor(p.Name.Space)
+			b.WriteString(fmt.Sprintf(`<%s:%s xmlns:%s="%s"/>`, prefix, p.Name.Local, prefix, p.Name.Space))
+		}
+		b.WriteString(`</D:prop></D:remove>`)
+	}
+
+	b.WriteString(`</D:propertyupdate>`)
+	return b.String()
+}
+
+// xmlEscape escapes the handful of characters that aren't valid raw inside
+// XML element content.
+func xmlEscape(s string) string {
+	r := strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+	)
+	return r.Replace(s)
+}
@@ -0,0 +1,92 @@
+package gowebdav
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestBuildPropfindBodyAllProp(t *testing.T) {
+	got := buildPropfindBody(nil)
+	if !strings.Contains(got, "<D:allprop/>") {
+		t.Errorf("body = %q, want an allprop request for no props", got)
+	}
+}
+
+func TestBuildPropfindBodyNamedProps(t *testing.T) {
+	props := []xml.Name{
+		{Space: "DAV:", Local: "getlastmodified"},
+		{Space: "http://owncloud.org/ns", Local: "fileid"},
+	}
+
+	got := buildPropfindBody(props)
+	if !strings.Contains(got, `<D:getlastmodified xmlns:D="DAV:"/>`) {
+		t.Errorf("body missing getlastmodified element: %s", got)
+	}
+	if !strings.Contains(got, `xmlns:ns1="http://owncloud.org/ns"`) {
+		t.Errorf("body missing a distinct namespace prefix for the owncloud ns: %s", got)
+	}
+	if !strings.Contains(got, "<ns1:fileid") {
+		t.Errorf("body missing fileid element under its own prefix: %s", got)
+	}
+}
+
+func TestBuildProppatchBodySetAndRemove(t *testing.T) {
+	set := []Property{{Name: xml.Name{Space: "DAV:", Local: "getlastmodified"}, Value: "Mon, 01 Jan 2024 00:00:00 GMT"}}
+	remove := []Property{{Name: xml.Name{Space: "http://owncloud.org/ns", Local: "fileid"}}}
+
+	got := buildProppatchBody(set, remove)
+	if !strings.Contains(got, "<D:set>") || !strings.Contains(got, "Mon, 01 Jan 2024 00:00:00 GMT") {
+		t.Errorf("body missing set block with the literal value: %s", got)
+	}
+	if !strings.Contains(got, "<D:remove>") || !strings.Contains(got, "<ns1:fileid") {
+		t.Errorf("body missing remove block for fileid: %s", got)
+	}
+}
+
+func TestXMLEscape(t *testing.T) {
+	got := xmlEscape(`<a & b>`)
+	want := "&lt;a &amp; b&gt;"
+	if got != want {
+		t.Errorf("xmlEscape = %q, want %q", got, want)
+	}
+}
+
+func TestParsePropPatchResultAllApplied(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<D:multistatus xmlns:D="DAV:">
+  <D:response>
+    <D:href>/dav/f</D:href>
+    <D:propstat>
+      <D:prop><D:getlastmodified/></D:prop>
+      <D:status>HTTP/1.1 200 OK</D:status>
+    </D:propstat>
+  </D:response>
+</D:multistatus>`
+
+	if err := parsePropPatchResult("/f", strings.NewReader(body)); err != nil {
+		t.Errorf("want nil error when every propstat is 2xx, got %v", err)
+	}
+}
+
+func TestParsePropPatchResultRejectedProperty(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<D:multistatus xmlns:D="DAV:">
+  <D:response>
+    <D:href>/dav/f</D:href>
+    <D:propstat>
+      <D:prop><D:getlastmodified/></D:prop>
+      <D:status>HTTP/1.1 409 Conflict</D:status>
+    </D:propstat>
+  </D:response>
+</D:multistatus>`
+
+	err := parsePropPatchResult("/f", strings.NewReader(body))
+	ppErr, ok := err.(*PropPatchError)
+	if !ok {
+		t.Fatalf("err = %v (%T), want *PropPatchError", err, err)
+	}
+	if len(ppErr.Props) != 1 || ppErr.Props[0].Status != 409 {
+		t.Errorf("Props = %+v, want one entry with status 409", ppErr.Props)
+	}
+}
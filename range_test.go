@@ -0,0 +1,73 @@
+package gowebdav
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newRangeResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestWrapRangeResponsePartialContent(t *testing.T) {
+	rs := newRangeResponse(http.StatusPartialContent, "llo")
+
+	rc, err := wrapRangeResponse(rs, "/f", 2, 3)
+	if err != nil {
+		t.Fatalf("wrapRangeResponse: %v", err)
+	}
+	defer rc.Close()
+
+	got, _ := io.ReadAll(rc)
+	if string(got) != "llo" {
+		t.Errorf("got %q, want the 206 body passed through untouched", got)
+	}
+}
+
+func TestWrapRangeResponseFullBodyFallback(t *testing.T) {
+	rs := newRangeResponse(http.StatusOK, "hello world")
+
+	rc, err := wrapRangeResponse(rs, "/f", 6, 5)
+	if err != nil {
+		t.Fatalf("wrapRangeResponse: %v", err)
+	}
+	defer rc.Close()
+
+	got, _ := io.ReadAll(rc)
+	if string(got) != "world" {
+		t.Errorf("got %q, want offset 6 discarded and length 5 kept from a server that ignored Range", got)
+	}
+}
+
+func TestWrapRangeResponseError(t *testing.T) {
+	rs := newRangeResponse(http.StatusNotFound, "")
+
+	if _, err := wrapRangeResponse(rs, "/f", 0, 1); err == nil {
+		t.Error("want an error for a non-200/206 status")
+	}
+}
+
+func TestIsRangeHonouredPartialContent(t *testing.T) {
+	honoured, recognized := isRangeHonoured(http.StatusPartialContent)
+	if !recognized || !honoured {
+		t.Errorf("honoured, recognized = %v, %v, want true, true for 206", honoured, recognized)
+	}
+}
+
+func TestIsRangeHonouredFullBodyFallback(t *testing.T) {
+	honoured, recognized := isRangeHonoured(http.StatusOK)
+	if !recognized || honoured {
+		t.Errorf("honoured, recognized = %v, %v, want false, true for 200 - the server ignored Range", honoured, recognized)
+	}
+}
+
+func TestIsRangeHonouredUnrecognizedStatus(t *testing.T) {
+	if _, recognized := isRangeHonoured(http.StatusNotFound); recognized {
+		t.Error("want recognized=false for a non-200/206 status")
+	}
+}
@@ -4,12 +4,28 @@ import (
 	"bytes"
 	"context"
 	"io"
-	"log"
 	"net/http"
 	"path"
 	"strings"
 )
 
+// requestURL resolves path to the URL a request should actually hit: an
+// already-absolute path (http:// or https://, as built by things like the
+// Nextcloud chunked-upload endpoint that lives outside c.root) is used
+// as-is, everything else is resolved against c.root as before.
+func (c *Client) requestURL(path string) string {
+	return resolveRequestURL(c.root, path)
+}
+
+// resolveRequestURL is the pure logic behind requestURL, split out so it
+// can be tested without constructing a Client.
+func resolveRequestURL(root, path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return PathEscape(path)
+	}
+	return PathEscape(Join(root, path))
+}
+
 func (c *Client) req(ctx context.Context, method, path string, body io.Reader, intercept func(*http.Request)) (req *http.Response, err error) {
 	var r *http.Request
 	var retryBuf io.Reader
@@ -31,9 +47,9 @@ func (c *Client) req(ctx context.Context, method, path string, body io.Reader, i
 			retryBuf = buff
 			body = io.TeeReader(body, buff)
 		}
-		r, err = http.NewRequest(method, PathEscape(Join(c.root, path)), body)
+		r, err = http.NewRequest(method, c.requestURL(path), body)
 	} else {
-		r, err = http.NewRequest(method, PathEscape(Join(c.root, path)), nil)
+		r, err = http.NewRequest(method, c.requestURL(path), nil)
 	}
 
 	if err != nil {
@@ -54,6 +70,10 @@ func (c *Client) req(ctx context.Context, method, path string, body io.Reader, i
 
 	auth.Authorize(r, method, path)
 
+	if token := lockToken(ctx); token != "" {
+		r.Header.Set("If", "(<"+token+">)")
+	}
+
 	if intercept != nil {
 		intercept(r)
 	}
@@ -68,20 +88,15 @@ func (c *Client) req(ctx context.Context, method, path string, body io.Reader, i
 	}
 
 	if rs.StatusCode == 401 && auth.Type() == "NoAuth" {
-		wwwAuthenticateHeader := strings.ToLower(rs.Header.Get("Www-Authenticate"))
-
-		if strings.Index(wwwAuthenticateHeader, "digest") > -1 {
-			c.authMutex.Lock()
-			c.auth = &DigestAuth{auth.User(), auth.Pass(), digestParts(rs)}
-			c.authMutex.Unlock()
-		} else if strings.Index(wwwAuthenticateHeader, "basic") > -1 {
-			c.authMutex.Lock()
-			c.auth = &BasicAuth{auth.User(), auth.Pass()}
-			c.authMutex.Unlock()
-		} else {
+		_, factory, ok := c.lookupAuthFactory(rs.Header.Get("Www-Authenticate"))
+		if !ok {
 			return rs, newPathError("Authorize", c.root, rs.StatusCode)
 		}
 
+		c.authMutex.Lock()
+		c.auth = factory(rs, auth.User(), auth.Pass())
+		c.authMutex.Unlock()
+
 		// retryBuf will be nil if body was nil initially so no check
 		// for body == nil is required here.
 		return c.req(ctx, method, path, retryBuf, intercept)
@@ -179,8 +194,14 @@ func (c *Client) copymove(ctx context.Context, method string, oldpath string, ne
 		return nil
 
 	case 207:
-		// TODO handle multistat errors, worst case ...
-		log.Printf("TODO handle %s - %s multistatus result %s\n", method, oldpath, String(data))
+		ms, perr := parseMultiStatus(method, oldpath, data)
+		if perr != nil {
+			return perr
+		}
+		if len(ms.Failures()) == 0 {
+			return nil
+		}
+		return ms
 
 	case 409:
 		err := c.createParentCollection(ctx, newpath)
@@ -195,6 +216,12 @@ func (c *Client) copymove(ctx context.Context, method string, oldpath string, ne
 }
 
 func (c *Client) put(ctx context.Context, path string, stream io.Reader) (status int, err error) {
+	if length, ok := readerLength(stream); ok {
+		if cfg := c.chunkedUploadConfigFor(ctx, length); cfg != nil {
+			return c.chunkedPut(ctx, path, stream, length, cfg)
+		}
+	}
+
 	rs, err := c.req(ctx, "PUT", path, stream, nil)
 	if err != nil {
 		return